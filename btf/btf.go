@@ -0,0 +1,160 @@
+// Copyright 1988 John Gilbert and Tim Peierls
+// All rights reserved.
+
+package btf
+
+import (
+	"fmt"
+
+	"github.com/rwlincoln/gp"
+)
+
+// Options configures FactorBTF.  It is passed by value, the way gp's own
+// Factor takes its pivoting parameters, rather than a pointer, since
+// it is small and not mutated by the driver.
+type Options struct {
+	Pivot   int
+	Pthresh float64
+	Dthresh float64
+	Nzcount int
+}
+
+// Block is one diagonal block of a matrix factored by FactorBTF: either
+// a full LU factorization for blocks larger than 1x1, or, for the common
+// 1x1 case, just the scalar pivot itself.
+type Block struct {
+	cols []int // original column numbers making up this block, in order
+	diag float64
+	num  *gp.Numeric
+}
+
+// BTF is the result of FactorBTF: a block upper triangular permutation
+// of a, with each diagonal block factored independently and the strictly
+// upper part kept as a plain sparse CSC for use during Solve.
+type BTF struct {
+	n       int
+	p       []int // p[k] is the original column index of block position k
+	match   []int // match[j] is the original row matched to column j
+	r       []int // block boundaries into p, as returned by StrongComponents
+	blocks  []Block
+	offdiag *gp.CSC
+}
+
+// FactorBTF computes a permutation of a into block upper triangular form
+// via MaxMatching and StrongComponents, then factors each diagonal block
+// with gp.Factor (or, for a 1x1 block, a plain scalar divide).  The
+// strictly upper triangular part of the permuted matrix is kept as-is
+// and consulted only during Solve, block by block from bottom to top.
+func FactorBTF(a *gp.CSC, opts Options) (*BTF, error) {
+	match := MaxMatching(a)
+	for j := 1; j <= a.N; j++ {
+		if match[j] == 0 {
+			return nil, fmt.Errorf("FactorBTF: column %v could not be matched to a row; a is structurally singular", j)
+		}
+	}
+
+	p, r := StrongComponents(a, match)
+
+	bt := &BTF{n: a.N, p: p, match: match, r: r}
+	bt.offdiag = extractOffdiag(a, match, p, r)
+
+	for b := 0; b < len(r)-1; b++ {
+		cols := p[r[b]-1 : r[b+1]-1]
+		if len(cols) == 1 {
+			j := cols[0]
+			i := match[j]
+			diag := diagEntry(a, i, j)
+			bt.blocks = append(bt.blocks, Block{cols: cols, diag: diag})
+			continue
+		}
+
+		sub := extractBlock(a, match, cols)
+		num, _, err := gp.Factor(sub.A, sub.Arow, sub.Acolst, len(cols), opts.Pivot, opts.Pthresh, opts.Dthresh, opts.Nzcount)
+		if err != nil {
+			return nil, fmt.Errorf("FactorBTF: block of %v columns: %v", len(cols), err)
+		}
+		bt.blocks = append(bt.blocks, Block{cols: cols, num: num})
+	}
+
+	return bt, nil
+}
+
+// diagEntry returns a(i,j), or 0 if a has no entry there.
+func diagEntry(a *gp.CSC, i, j int) float64 {
+	for nzptr := a.Acolst[j]; nzptr <= a.Acolst[j+1]-1; nzptr++ {
+		if a.Arow[nzptr] == i {
+			return a.A[nzptr]
+		}
+	}
+	return 0.0
+}
+
+// extractBlock builds a dense-free CSC containing only the rows and
+// columns in cols, renumbered 1..len(cols) in the order given, for
+// handoff to gp.Factor.
+func extractBlock(a *gp.CSC, match, cols []int) *gp.CSC {
+	localCol := make(map[int]int, len(cols))
+	for k, j := range cols {
+		localCol[match[j]] = k + 1
+	}
+
+	acolst := make([]int, len(cols)+2)
+	av := []float64{0}
+	arow := []int{0}
+	acolst[1] = 1
+	for k, j := range cols {
+		for nzptr := a.Acolst[j]; nzptr <= a.Acolst[j+1]-1; nzptr++ {
+			row := a.Arow[nzptr]
+			lr, ok := localCol[row]
+			if !ok {
+				continue // strictly upper entry: belongs to bt.offdiag, not this block
+			}
+			av = append(av, a.A[nzptr])
+			arow = append(arow, lr)
+		}
+		acolst[k+2] = len(av)
+	}
+
+	return &gp.CSC{N: len(cols), A: av, Arow: arow, Acolst: acolst}
+}
+
+// extractOffdiag copies every entry of a that lands strictly above its
+// diagonal block in the permuted matrix, renumbered into block order, so
+// Solve can apply it without re-touching the original CSC's numbering.
+func extractOffdiag(a *gp.CSC, match, p, r []int) *gp.CSC {
+	n := a.N
+	blockOf := make([]int, n+1)
+	for b := 0; b < len(r)-1; b++ {
+		for _, j := range p[r[b]-1 : r[b+1]-1] {
+			blockOf[j] = b
+		}
+	}
+	posOf := make([]int, n+1)
+	for k, j := range p {
+		posOf[j] = k + 1
+	}
+	rowOf := make([]int, n+1)
+	for j := 1; j <= n; j++ {
+		rowOf[match[j]] = j
+	}
+
+	acolst := make([]int, n+2)
+	av := []float64{0}
+	arow := []int{0}
+	acolst[1] = 1
+	for k := 0; k < n; k++ {
+		j := p[k]
+		for nzptr := a.Acolst[j]; nzptr <= a.Acolst[j+1]-1; nzptr++ {
+			row := a.Arow[nzptr]
+			i := rowOf[row]
+			if blockOf[i] >= blockOf[j] {
+				continue // diagonal-block entry, already in bt.blocks
+			}
+			av = append(av, a.A[nzptr])
+			arow = append(arow, posOf[i])
+		}
+		acolst[k+2] = len(av)
+	}
+
+	return &gp.CSC{N: n, A: av, Arow: arow, Acolst: acolst}
+}
@@ -0,0 +1,95 @@
+// Copyright 1988 John Gilbert and Tim Peierls
+// All rights reserved.
+
+package btf
+
+import (
+	"math"
+	"testing"
+
+	"github.com/rwlincoln/gp"
+)
+
+// TestFactorBTFSolve drives FactorBTF and Solve end to end on
+//
+//	A = [4 1 2]
+//	    [1 5 0]
+//	    [0 0 6]
+//
+// Columns 1 and 2 reference each other's rows and so land in one
+// strongly connected component -- a genuine 2x2 diagonal block that
+// exercises extractBlock and a real gp.Factor call, not just the 1x1
+// diagEntry fast path. Column 3 only reaches the block through its
+// entry in row 1, giving a non-trivial off-diagonal block that
+// exercises extractOffdiag and the rhs propagation in Solve.
+func TestFactorBTFSolve(t *testing.T) {
+	a := &gp.CSC{
+		N:      3,
+		A:      []float64{0, 4, 1, 1, 5, 2, 6},
+		Arow:   []int{0, 1, 2, 1, 2, 1, 3},
+		Acolst: []int{0, 1, 3, 5, 7},
+	}
+
+	bt, err := FactorBTF(a, Options{})
+	if err != nil {
+		t.Fatalf("FactorBTF: %v", err)
+	}
+
+	var twoByTwo bool
+	for _, blk := range bt.blocks {
+		if len(blk.cols) == 2 {
+			twoByTwo = true
+		}
+	}
+	if !twoByTwo {
+		t.Fatalf("expected a 2-column block from the columns 1/2 cycle, blocks = %+v", bt.blocks)
+	}
+
+	x, err := bt.Solve([]float64{7, 11, 12})
+	if err != nil {
+		t.Fatalf("Solve: %v", err)
+	}
+	want := []float64{4.0 / 19.0, 41.0 / 19.0, 2.0}
+	for i := range want {
+		if math.Abs(x[i]-want[i]) > 1e-9 {
+			t.Errorf("x[%v] = %v, want %v", i, x[i], want[i])
+		}
+	}
+}
+
+// TestFactorBTFSolveAllSingletons covers the 1x1-blocks-only path (the
+// diagEntry fast path in FactorBTF, and the scalar-divide fast path in
+// Solve), on the lower triangular
+//
+//	A = [2 0 0]
+//	    [1 3 0]
+//	    [0 1 4]
+func TestFactorBTFSolveAllSingletons(t *testing.T) {
+	a := &gp.CSC{
+		N:      3,
+		A:      []float64{0, 2, 1, 3, 1, 4},
+		Arow:   []int{0, 1, 2, 2, 3, 3},
+		Acolst: []int{0, 1, 3, 5, 6},
+	}
+
+	bt, err := FactorBTF(a, Options{})
+	if err != nil {
+		t.Fatalf("FactorBTF: %v", err)
+	}
+	for _, blk := range bt.blocks {
+		if len(blk.cols) != 1 {
+			t.Fatalf("expected only 1x1 blocks for a triangular matrix, got %+v", bt.blocks)
+		}
+	}
+
+	x, err := bt.Solve([]float64{2, 5, 9})
+	if err != nil {
+		t.Fatalf("Solve: %v", err)
+	}
+	want := []float64{1.0, 4.0 / 3.0, 23.0 / 12.0}
+	for i := range want {
+		if math.Abs(x[i]-want[i]) > 1e-9 {
+			t.Errorf("x[%v] = %v, want %v", i, x[i], want[i])
+		}
+	}
+}
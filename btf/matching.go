@@ -0,0 +1,51 @@
+// Copyright 1988 John Gilbert and Tim Peierls
+// All rights reserved.
+
+// Package btf permutes a matrix into block upper triangular form before
+// handing each diagonal block to the ordinary gp factorization, the way
+// KLU's BTF driver does for circuit and chemical-network matrices whose
+// structure is reducible.
+package btf
+
+import "github.com/rwlincoln/gp"
+
+// MaxMatching computes a maximum bipartite matching between the columns
+// and rows of a, using Duff's algorithm: repeatedly look for an
+// augmenting path out of each unmatched column via depth-first search
+// over the column's nonzero rows.
+//
+// MaxMatching returns match, where match[j] is the row matched to column
+// j (1-based, as elsewhere in this codebase), or 0 if column j could not
+// be matched.  A full matching (no zero entries) means a can be
+// permuted to have a zero-free diagonal before block-triangularization.
+func MaxMatching(a *gp.CSC) []int {
+	n := a.N
+	rowmatch := make([]int, n+1)
+	colmatch := make([]int, n+1)
+
+	for j := 1; j <= n; j++ {
+		visited := make([]bool, n+1)
+		augment(a, j, rowmatch, colmatch, visited)
+	}
+
+	return colmatch
+}
+
+// augment looks for an augmenting path out of column col in the
+// bipartite graph of a's nonzeros, extending rowmatch/colmatch in place
+// if one is found.
+func augment(a *gp.CSC, col int, rowmatch, colmatch []int, visited []bool) bool {
+	for nzptr := a.Acolst[col]; nzptr <= a.Acolst[col+1]-1; nzptr++ {
+		row := a.Arow[nzptr]
+		if visited[row] {
+			continue
+		}
+		visited[row] = true
+		if rowmatch[row] == 0 || augment(a, rowmatch[row], rowmatch, colmatch, visited) {
+			rowmatch[row] = col
+			colmatch[col] = row
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+// Copyright 1988 John Gilbert and Tim Peierls
+// All rights reserved.
+
+package btf
+
+import "github.com/rwlincoln/gp"
+
+// StrongComponents computes Tarjan's strongly connected components of
+// the digraph on a's columns induced by match: there is an edge j -> k
+// whenever column j has a nonzero entry in row match[k], the row matched
+// to column k.  Components are returned in reverse
+// topological order, which is exactly the column order that puts a into
+// block upper triangular form.
+//
+// StrongComponents returns p, the permutation of columns (and, via
+// match, of rows) into block order, and r, where r[b]:r[b+1]-1 are the
+// indices of p that make up block b, for b = 0 to len(r)-2.  r has one
+// more entry than there are blocks, with r[len(r)-1] == a.N+1.
+func StrongComponents(a *gp.CSC, match []int) (p, r []int) {
+	n := a.N
+	colOfRow := make([]int, n+1)
+	for j := 1; j <= n; j++ {
+		if match[j] != 0 {
+			colOfRow[match[j]] = j
+		}
+	}
+
+	t := &tarjan{
+		a:        a,
+		colOfRow: colOfRow,
+		index:    make([]int, n+1),
+		lowlink:  make([]int, n+1),
+		onstack:  make([]bool, n+1),
+		next:     1,
+	}
+
+	for j := 1; j <= n; j++ {
+		if t.index[j] == 0 {
+			t.strongconnect(j)
+		}
+	}
+
+	// t.comps holds components in the order Tarjan discovers them, which
+	// is already reverse topological order for the condensation graph.
+	r = make([]int, 0, len(t.comps)+1)
+	p = make([]int, 0, n)
+	r = append(r, 1)
+	for _, comp := range t.comps {
+		p = append(p, comp...)
+		r = append(r, len(p)+1)
+	}
+
+	return p, r
+}
+
+// tarjan holds the working state of Tarjan's algorithm, implemented
+// iteratively to avoid recursion depth limits on large matrices.
+type tarjan struct {
+	a        *gp.CSC
+	colOfRow []int
+	index    []int
+	lowlink  []int
+	onstack  []bool
+	stack    []int
+	next     int
+	comps    [][]int
+}
+
+type tarjanFrame struct {
+	v     int
+	nzptr int
+	nzend int
+}
+
+func (t *tarjan) strongconnect(v int) {
+	work := []tarjanFrame{{v: v, nzptr: t.a.Acolst[v], nzend: t.a.Acolst[v+1] - 1}}
+	t.index[v] = t.next
+	t.lowlink[v] = t.next
+	t.next++
+	t.stack = append(t.stack, v)
+	t.onstack[v] = true
+
+	for len(work) > 0 {
+		fr := &work[len(work)-1]
+		v := fr.v
+
+		advanced := false
+		for fr.nzptr <= fr.nzend {
+			row := t.a.Arow[fr.nzptr]
+			fr.nzptr++
+			w := t.colOfRow[row]
+			if w == 0 || w == v {
+				continue
+			}
+			if t.index[w] == 0 {
+				t.index[w] = t.next
+				t.lowlink[w] = t.next
+				t.next++
+				t.stack = append(t.stack, w)
+				t.onstack[w] = true
+				work = append(work, tarjanFrame{v: w, nzptr: t.a.Acolst[w], nzend: t.a.Acolst[w+1] - 1})
+				advanced = true
+				break
+			} else if t.onstack[w] {
+				if t.index[w] < t.lowlink[v] {
+					t.lowlink[v] = t.index[w]
+				}
+			}
+		}
+		if advanced {
+			continue
+		}
+
+		// All of v's neighbors are explored; pop v and, if it is a root,
+		// peel its component off the stack.
+		work = work[:len(work)-1]
+		if len(work) > 0 {
+			parent := &work[len(work)-1]
+			if t.lowlink[v] < t.lowlink[parent.v] {
+				t.lowlink[parent.v] = t.lowlink[v]
+			}
+		}
+
+		if t.lowlink[v] == t.index[v] {
+			var comp []int
+			for {
+				w := t.stack[len(t.stack)-1]
+				t.stack = t.stack[:len(t.stack)-1]
+				t.onstack[w] = false
+				comp = append(comp, w)
+				if w == v {
+					break
+				}
+			}
+			t.comps = append(t.comps, comp)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 1988 John Gilbert and Tim Peierls
+// All rights reserved.
+
+package btf
+
+import "fmt"
+
+// Solve solves Ax = b for the matrix factored by FactorBTF, given b in
+// the original row numbering.  Because the permuted matrix is block
+// upper triangular, blocks are solved from bottom to top: once a block's
+// unknowns are known, its contribution is subtracted from the right-hand
+// side of every block above it via bt.offdiag before that block is
+// solved in turn.
+func (bt *BTF) Solve(b []float64) ([]float64, error) {
+	rhs := make([]float64, bt.n)
+	for k, j := range bt.p {
+		rhs[k] = b[bt.match[j]-1]
+	}
+
+	x := make([]float64, bt.n)
+	for bi := len(bt.blocks) - 1; bi >= 0; bi-- {
+		blk := bt.blocks[bi]
+		start := bt.r[bi]
+		end := bt.r[bi+1] - 1
+
+		if blk.num == nil {
+			// 1x1 fast path: a scalar divide.
+			if blk.diag == 0.0 {
+				return nil, fmt.Errorf("btf: zero pivot in 1x1 block %v", bi)
+			}
+			x[start-1] = rhs[start-1] / blk.diag
+		} else {
+			sol, err := blk.num.Solve(rhs[start-1 : end])
+			if err != nil {
+				return nil, fmt.Errorf("btf: block of %v columns: %v", len(blk.cols), err)
+			}
+			copy(x[start-1:end], sol)
+		}
+
+		// Propagate this block's solution into the right-hand sides of
+		// every block above it via the stored off-diagonal entries.
+		for col := start; col <= end; col++ {
+			xcol := x[col-1]
+			if xcol == 0.0 {
+				continue
+			}
+			for nzptr := bt.offdiag.Acolst[col]; nzptr <= bt.offdiag.Acolst[col+1]-1; nzptr++ {
+				row := bt.offdiag.Arow[nzptr]
+				rhs[row-1] -= bt.offdiag.A[nzptr] * xcol
+			}
+		}
+	}
+
+	out := make([]float64, bt.n)
+	for k, j := range bt.p {
+		out[j-1] = x[k]
+	}
+	return out, nil
+}
@@ -0,0 +1,15 @@
+// Copyright 1988 John Gilbert and Tim Peierls
+// All rights reserved.
+
+package gp
+
+// off is the conversion between this package's 1-based row/column
+// numbering (kept throughout to match the original Fortran GPLU code)
+// and Go's 0-based slice indexing.  Every array that carries a 1-based
+// index (lurow, lcolst, ucolst, rperm, cperm, dense, and so on) is
+// allocated one element larger than strictly necessary, with index 0
+// left unused, so that a logical index i is always stored at plain Go
+// index i; off is subtracted for symmetry with call sites that compute
+// an index arithmetically (e.g. jcol-off) rather than because it is
+// ever nonzero.
+const off = 0
@@ -0,0 +1,17 @@
+// Copyright 1988 John Gilbert and Tim Peierls
+// All rights reserved.
+
+package gp
+
+// CSC is an n-by-n matrix in compressed-sparse-column form: column j's
+// entries are A[Acolst[j]] through A[Acolst[j+1]-1], with row numbers in
+// the matching entries of Arow.  All three of N, Acolst and Arow use the
+// same 1-based numbering as the rest of this package, with index 0 of A,
+// Arow and Acolst unused; Acolst holds entries 1 through N+1, with
+// Acolst[N+1] == len(A).
+type CSC struct {
+	N      int
+	A      []float64
+	Arow   []int
+	Acolst []int
+}
@@ -0,0 +1,141 @@
+// Copyright 1988 John Gilbert and Tim Peierls
+// All rights reserved.
+
+package gp
+
+import "fmt"
+
+// Numeric holds the numeric LU factorization PAQ = LU produced by
+// Factor, or by Refactor/PartialRefactor reusing a prior Symbolic: the
+// combined L\U values in lu, their row structure in lurow, the column
+// boundaries lcolst/ucolst that split each column into its U part
+// followed by its L part, and the row/column permutations rperm/cperm.
+type Numeric struct {
+	n      int
+	lu     []float64
+	lurow  []int
+	lcolst []int
+	ucolst []int
+	rperm  []int
+	cperm  []int
+}
+
+// Factor computes the LU factorization PAQ = LU of the n-by-n matrix A
+// given in compressed-sparse-column form (a, arow, acolst).  Column
+// order Q is fixed to the identity; row order P is chosen by lucopy's
+// pivoting, controlled by pivot, pthresh, dthresh and nzcount (see
+// lucopy's doc comment for their meaning).
+//
+// Factor drives the classical column-at-a-time loop: ludfs predicts the
+// fill pattern of column jcol by depth-first search, lucopy copies,
+// pivots and divides it, and prune applies Eisenstat-Liu symmetric
+// pruning to the columns that just became pivotal, shrinking the DFS
+// work of every later call to ludfs.
+//
+// Besides the Numeric factorization, Factor returns the Symbolic that
+// records its nonzero pattern, permutations and pivoting parameters;
+// callers who expect to refactor the same pattern repeatedly should hold
+// onto it and pass it to Refactor or PartialRefactor instead of calling
+// Factor again.
+func Factor(a []float64, arow, acolst []int, n, pivot int, pthresh, dthresh float64, nzcount int) (*Numeric, *Symbolic, error) {
+	nzmax := 2*(acolst[n+1-off]-acolst[1-off]) + n
+
+	lurow := make([]int, nzmax+1)
+	lu := make([]float64, nzmax+1)
+	lcolst := make([]int, n+2)
+	ucolst := make([]int, n+2)
+	rperm := make([]int, n+1)
+	cperm := make([]int, n+1)
+	dense := make([]float64, n+1)
+	found := make([]int, n+1)
+	parent := make([]int, n+1)
+	child := make([]int, n+1)
+	pattern := make([]int, n+1)
+	twork := make([]float64, n+1)
+	Lpend := make([]int, n+1)
+	rowmax := make([]float64, n+1)
+
+	for j := 1; j <= n; j++ {
+		cperm[j-off] = j
+	}
+
+	lastlu := 0
+	ucolst[1-off] = 1
+
+	for jcol := 1; jcol <= n; jcol++ {
+		ucolst[jcol-off] = lastlu + 1
+
+		if err := ludfs(jcol, a, arow, acolst, &lastlu, lurow, lcolst, ucolst, rperm, cperm, dense, found, parent, child, Lpend); err != nil {
+			return nil, nil, fmt.Errorf("Factor: %v", err)
+		}
+
+		// ludfs appended the raw (pre-compaction) pattern straight past
+		// lastlu; record where it ends so lucopy knows the bounds of the
+		// column it is about to copy, pivot and divide.
+		ucolst[jcol+1-off] = lastlu + 1
+
+		// dense still holds the raw column of A that ludfs scattered into
+		// it; apply the rank-1 update from every already-pivotal column
+		// it depends on before lucopy copies it out, exactly the way
+		// refupdate replays this same sequence during Refactor.
+		refupdate(jcol, lu, lurow, lcolst, ucolst, rperm, dense)
+
+		// Mark this column's candidate nonzeros, and its structural
+		// diagonal, for lucopy; remember which rows we touched so the
+		// pattern array can be cleared again before the next column.
+		touched := make([]int, 0, lastlu-ucolst[jcol-off]+1)
+		for nzptr := ucolst[jcol-off]; nzptr <= lastlu; nzptr++ {
+			irow := lurow[nzptr-off]
+			touched = append(touched, irow)
+			if irow == cperm[jcol-off] {
+				pattern[irow-off] = 2
+			} else {
+				pattern[irow-off] = 1
+			}
+		}
+
+		zpivot, err := lucopy(pivot, pthresh, dthresh, 1.0, nzcount, jcol, n, &lastlu, lu, lurow, lcolst, ucolst, rperm, cperm, dense, pattern, twork, rowmax)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Factor: %v", err)
+		}
+		if zpivot <= 0 {
+			return nil, nil, fmt.Errorf("Factor: no pivot found for column %v", jcol)
+		}
+
+		for _, irow := range touched {
+			pattern[irow-off] = 0
+		}
+
+		// Column jcol just became pivotal; let prune shrink the DFS
+		// range of any earlier column whose L part reaches jcol only
+		// because of A's structural symmetry.
+		Lpend[jcol-off] = ucolst[jcol+1-off]
+		if err := prune(jcol, lurow, lcolst, ucolst, rperm, Lpend); err != nil {
+			return nil, nil, fmt.Errorf("Factor: %v", err)
+		}
+	}
+
+	num := &Numeric{
+		n:      n,
+		lu:     lu,
+		lurow:  lurow,
+		lcolst: lcolst,
+		ucolst: ucolst,
+		rperm:  rperm,
+		cperm:  cperm,
+	}
+	sym := &Symbolic{
+		n:       n,
+		lurow:   lurow,
+		lcolst:  lcolst,
+		ucolst:  ucolst,
+		rperm:   rperm,
+		cperm:   cperm,
+		pivot:   pivot,
+		pthresh: pthresh,
+		dthresh: dthresh,
+		nzcount: nzcount,
+	}
+
+	return num, sym, nil
+}
@@ -0,0 +1,60 @@
+// Copyright 1988 John Gilbert and Tim Peierls
+// All rights reserved.
+
+package gp
+
+import (
+	"math"
+	"testing"
+)
+
+// TestFactorSolve drives Factor end to end on
+//
+//	A = [3  5]
+//	    [6 17]
+//
+// with pivoting disabled (pivot 0 always takes the structural
+// diagonal), so the identity permutation is expected and A should
+// factor into the same L = [[1,0],[2,1]], U = [[3,5],[0,7]] used by
+// solve_test.go's hand-built fixture.  Solving Ax = b for b = [1,0] and
+// checking the result against that fixture's known answer exercises
+// ludfs, lucopy, prune and Solve together, not just that Factor returns
+// without error.
+func TestFactorSolve(t *testing.T) {
+	a := []float64{0, 3, 6, 5, 17}
+	arow := []int{0, 1, 2, 1, 2}
+	acolst := []int{0, 1, 3, 5}
+
+	num, _, err := Factor(a, arow, acolst, 2, 0, 0.0, 0.0, 0)
+	if err != nil {
+		t.Fatalf("Factor: %v", err)
+	}
+
+	for i := 1; i <= 2; i++ {
+		if num.rperm[i] != i {
+			t.Errorf("rperm[%v] = %v, want %v (no pivoting should keep the identity)", i, num.rperm[i], i)
+		}
+	}
+
+	x, err := num.Solve([]float64{1, 0})
+	if err != nil {
+		t.Fatalf("Solve: %v", err)
+	}
+	wantX := []float64{17.0 / 21.0, -2.0 / 7.0}
+	for i := range wantX {
+		if math.Abs(x[i]-wantX[i]) > 1e-9 {
+			t.Errorf("Solve: x[%v] = %v, want %v", i, x[i], wantX[i])
+		}
+	}
+
+	y, err := num.SolveTranspose([]float64{1, 0})
+	if err != nil {
+		t.Fatalf("SolveTranspose: %v", err)
+	}
+	wantY := []float64{17.0 / 21.0, -5.0 / 21.0}
+	for i := range wantY {
+		if math.Abs(y[i]-wantY[i]) > 1e-9 {
+			t.Errorf("SolveTranspose: y[%v] = %v, want %v", i, y[i], wantY[i])
+		}
+	}
+}
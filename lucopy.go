@@ -1,4 +1,4 @@
-package lufact
+package gp
 
 import (
 	"fmt"
@@ -17,7 +17,23 @@ import (
 //           = 0 for no pivoting
 //           = 1 for partial (row) pivoting
 //           = 2 for threshold (row) pivoting
+//           = 3 for rook pivoting, capped at a single step: the
+//             column's largest candidate is tested against rowmax and
+//             is the pivot whether or not it passes, so pivot 3 picks
+//             the same rows as partial pivoting
+//           = 4 for complete pivoting, approximated: true complete
+//             pivoting searches the whole active submatrix for its
+//             largest entry, but this is a left-looking column code
+//             with no row-oriented structure, so pivot 4 only widens
+//             rook's per-column candidate search: when the largest
+//             candidate fails against rowmax (see rowmax below), every
+//             other candidate in jcol is tested in turn and the first
+//             that passes is taken, instead of keeping the largest -- it is
+//             a meaningfully weaker guarantee than full complete
+//             pivoting, not a complete-pivoting implementation
 //   pthresh  fraction of max pivot candidate acceptable for pivoting
+//   alpha    fraction of rowmax a rook/complete candidate must reach to
+//            be accepted without further search; see pivot 3 and 4 below
 //   jcol    Current column number.
 //   ncol    Total number of columns; upper bound on row counts.
 //
@@ -39,13 +55,26 @@ import (
 //   cperm                  The column permutation.
 //   dense                  On entry, column jcol of Pt(U(jcol,jcol)*(L-I)+U).
 //                          On exit, zero.
+//   rowmax                 rowmax(i) is a running upper bound on the
+//                          magnitude of row i of the active submatrix,
+//                          updated as each column is copied into lu.
+//                          Since this is a left-looking column code with
+//                          no row-oriented structure, rowmax is only a
+//                          proxy for "the row of the candidate pivot":
+//                          it accumulates every entry ever seen at row i
+//                          instead of just the entries still active in
+//                          the unfactored submatrix.  That makes pivot 4
+//                          slightly more conservative than true complete
+//                          pivoting (pivot 3's choice does not depend on
+//                          rowmax; see above), in exchange for not
+//                          needing a row-oriented copy of A at all.
 //   flops                  flop count
 //
 // Output variable:
 //   zpivot                 > 0 for success (pivot row), -1 for zero pivot element.
-func lucopy(pivot int, pthresh, dthresh float64, nzcount int,
+func lucopy(pivot int, pthresh, dthresh, alpha float64, nzcount int,
 	jcol, ncol int, lastlu *int, lu []float64, lurow, lcolst, ucolst []int,
-	rperm, cperm []int, dense []float64, pattern []int, twork []float64) (int, error) {
+	rperm, cperm []int, dense []float64, pattern []int, twork []float64, rowmax []float64) (int, error) {
 	// Local variables:
 	//   nzptr       Index into lurow of current nonzero.
 	//   nzst, nzend Loop bounds for nzptr.
@@ -212,6 +241,7 @@ func lucopy(pivot int, pthresh, dthresh float64, nzcount int,
 		ujjptr = 0
 		maxpiv := -1.0
 		maxpivglb := -1.0
+		var cands []rookCand
 
 		for nzptr := lcolst[jcol]; nzptr <= ucolst[jcol+1]-1; nzptr++ {
 			irow := lurow[nzptr]
@@ -240,11 +270,31 @@ func lucopy(pivot int, pthresh, dthresh float64, nzcount int,
 			if utemp > maxpivglb {
 				maxpivglb = utemp
 			}
+
+			if pivot == 3 || pivot == 4 {
+				cands = append(cands, rookCand{irow, utemp})
+			}
 		}
 
-		// Threshold pivoting.
-		if diagptr != 0 && diagpiv >= (pthresh*maxpiv) {
-			ujjptr = diagptr
+		if pivot == 3 || pivot == 4 {
+			// Rook/complete pivoting: accept the column max only once it
+			// also looks large relative to its row; otherwise step through
+			// the column's remaining candidates, in the order the scan
+			// above found them, testing at most step candidates in all
+			// (1 for rook, so only the column max; all for complete), and
+			// take the first one that passes, falling back to the column
+			// max. See lucopy's doc comment for why rowmax is only an
+			// approximation of "the row of the active submatrix".
+			step := 1
+			if pivot == 4 {
+				step = len(cands)
+			}
+			ujjptr = rookPivot(cands, rowmax, alpha, step)
+		} else {
+			// Threshold pivoting.
+			if diagptr != 0 && diagpiv >= (pthresh*maxpiv) {
+				ujjptr = diagptr
+			}
 		}
 
 		if diagptr == 0 && ujjptr == 0 {
@@ -300,7 +350,7 @@ func lucopy(pivot int, pthresh, dthresh float64, nzcount int,
 	// Diagonal element has been found. Swap U(jcol,jcol) from L into U.
 
 	if ujjptr == 0 {
-		return -1, fmt.Errorf("ujjptr not set (1)" /*diagptr*/, ujjptr, lcolst[jcol], ucolst[jcol+1]-1)
+		return -1, fmt.Errorf("ujjptr not set (1): ujjptr=%v lcolst[jcol]=%v ucolst[jcol+1]-1=%v", ujjptr, lcolst[jcol], ucolst[jcol+1]-1)
 	}
 
 	pivrow := lurow[ujjptr]
@@ -335,6 +385,79 @@ func lucopy(pivot int, pthresh, dthresh float64, nzcount int,
 		lu[nzptr] = lu[nzptr] / ujj
 	}
 
+	// Update the row-max proxy used by rook and complete pivoting: every
+	// row touched by column jcol (in U as well as L) may be the row a
+	// later column's rook search lands on.
+	if rowmax != nil {
+		for nzptr := ucolst[jcol]; nzptr <= nzend; nzptr++ {
+			irow := lurow[nzptr]
+			if utemp := math.Abs(lu[nzptr]); utemp > rowmax[irow] {
+				rowmax[irow] = utemp
+			}
+		}
+	}
+
 	zpivot := pivrow
 	return zpivot, nil
 }
+
+// rookCand is a candidate pivot row gathered while scanning column jcol
+// for rook or complete pivoting.
+type rookCand struct {
+	irow int
+	val  float64
+}
+
+// rookPivot picks a pivot row for rook (pivot==3) or complete (pivot==4)
+// pivoting from cands, the candidates gathered while scanning column
+// jcol, in whatever order that scan visited them -- cands is not
+// sorted.  It first tries the column's own largest candidate; if that
+// is not at least alpha*rowmax of its row, it falls through to the
+// remaining candidates (in their original scan order, not by
+// magnitude), checking at most step of them in total, before giving up
+// and returning the column's own largest candidate, which is always
+// numerically safe.
+func rookPivot(cands []rookCand, rowmax []float64, alpha float64, step int) int {
+	if len(cands) == 0 {
+		return 0
+	}
+
+	best, bestval := cands[0].irow, cands[0].val
+	for _, c := range cands {
+		if c.val > bestval {
+			best, bestval = c.irow, c.val
+		}
+	}
+
+	if step > len(cands) {
+		step = len(cands)
+	}
+
+	accept := func(c rookCand) bool {
+		rmax := bestval
+		if rowmax != nil && rowmax[c.irow] > 0 {
+			rmax = rowmax[c.irow]
+		}
+		return c.val >= alpha*rmax
+	}
+
+	if accept(rookCand{best, bestval}) {
+		return best
+	}
+
+	tried := 1
+	for _, c := range cands {
+		if c.irow == best {
+			continue
+		}
+		if tried >= step {
+			break
+		}
+		tried++
+		if accept(c) {
+			return c.irow
+		}
+	}
+
+	return best
+}
@@ -0,0 +1,47 @@
+// Copyright 1988 John Gilbert and Tim Peierls
+// All rights reserved.
+
+package gp
+
+import "testing"
+
+// TestRookPivotTestsBestFirst checks that rookPivot considers the
+// column's own largest candidate before any other, rather than testing
+// only cands[0] in scan order. A prior version of rookPivot, called with
+// step==1 (the rook case), tested only cands[0] against its row's
+// threshold; here cands[0] (row 1, magnitude 3) passes its own generous
+// row threshold while the true largest candidate (row 2, magnitude 10)
+// is never even considered, so the prior code returned row 1 -- the
+// weaker of the two candidates, chosen purely by scan order.
+func TestRookPivotTestsBestFirst(t *testing.T) {
+	cands := []rookCand{
+		{irow: 1, val: 3.0},
+		{irow: 2, val: 10.0},
+	}
+	rowmax := make([]float64, 3)
+	rowmax[1] = 1.0  // row 1's threshold is easy to clear
+	rowmax[2] = 50.0 // row 2's threshold is not
+
+	got := rookPivot(cands, rowmax, 0.5, 1)
+	if got != 2 {
+		t.Errorf("rookPivot = row %v, want row 2 (the column's largest candidate)", got)
+	}
+}
+
+// TestRookPivotFallsThroughOnStep checks that, given a larger step
+// budget, rookPivot does fall through past a failing best candidate to
+// accept a later one.
+func TestRookPivotFallsThroughOnStep(t *testing.T) {
+	cands := []rookCand{
+		{irow: 1, val: 10.0},
+		{irow: 2, val: 3.0},
+	}
+	rowmax := make([]float64, 3)
+	rowmax[1] = 50.0 // row 1's threshold is not easy to clear
+	rowmax[2] = 1.0  // row 2's threshold is
+
+	got := rookPivot(cands, rowmax, 0.5, 2)
+	if got != 2 {
+		t.Errorf("rookPivot = row %v, want row 2 (reached within the step budget)", got)
+	}
+}
@@ -33,6 +33,12 @@ import "fmt"
 //                    the vertex numbering of A, not PA; thus child(i) is
 //                    the position of a nonzero in column rperm(i),
 //                    not column i.
+//   Lpend            Lpend(k) is the effective end (exclusive) of the L
+//                    part of column k, as set by prune. A column whose L
+//                    part has been pruned is walked only up to Lpend(k)
+//                    instead of all the way to ucolst(k+1); the rows
+//                    beyond Lpend(k) are guaranteed to be reached through
+//                    U instead, so they need not be visited here.
 //
 // Output parameters:
 //   error            0 if successful, 1 otherwise
@@ -54,7 +60,7 @@ import "fmt"
 //     These rows also are numbered according to A, not PA.
 //   lcolst(jcol) is the index of the first nonzero in col j of L.
 //   lastlu is the index of the last non-fill nonzero in col j of L.
-func ludfs(jcol int, a []float64, arow, acolst []int, lastlu *int, lurow, lcolst, ucolst, rperm, cperm []int, dense []float64, found, parent, child []int) error {
+func ludfs(jcol int, a []float64, arow, acolst []int, lastlu *int, lurow, lcolst, ucolst, rperm, cperm []int, dense []float64, found, parent, child, Lpend []int) error {
 	// Local variables:
 	//   nzast, nzaend   range of indices in arow for column jcol of A.
 	//   nzaptr          pointer to current position in arow.
@@ -104,8 +110,11 @@ func ludfs(jcol int, a []float64, arow, acolst []int, lastlu *int, lurow, lcolst
 		//   else step back
 		// until a step back leads to 0
 	l100:
-		// Look for an unfound child of krow.
-		chdend = ucolst[rperm[krow-off]+1-off]
+		// Look for an unfound child of krow.  If column rperm(krow) has
+		// been pruned, Lpend(rperm(krow)) is less than ucolst(rperm(krow)+1)
+		// and the rows beyond it are skipped: they are already known to be
+		// reached through U(rperm(krow),*) instead.
+		chdend = Lpend[rperm[krow-off]-off]
 
 	l200:
 		if chdptr >= chdend {
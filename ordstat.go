@@ -0,0 +1,51 @@
+// Copyright 1988 John Gilbert and Tim Peierls
+// All rights reserved.
+
+package gp
+
+// dordstat finds the k-th smallest of arr[1..n] (1-based, as elsewhere
+// in this package) by quickselect, storing the result in *kth.  arr is
+// partially reordered in the process, the same way a partition step
+// would.  *ierr is set to 0 on success, or 1 if k is out of [1,n].
+//
+// This is the order-statistic helper lucopy uses to compute a drop
+// threshold when nzcount caps the number of entries kept per column
+// (magnitude-based "numerical dropping", as opposed to pattern-based).
+func dordstat(n, k int, arr []float64, kth *float64, ierr *int) {
+	if k < 1 || k > n {
+		*ierr = 1
+		return
+	}
+
+	lo, hi := 1, n
+	for lo < hi {
+		p := ordstatPartition(arr, lo, hi, (lo+hi)/2)
+		switch {
+		case k == p:
+			lo, hi = p, p
+		case k < p:
+			hi = p - 1
+		default:
+			lo = p + 1
+		}
+	}
+
+	*kth = arr[lo]
+	*ierr = 0
+}
+
+// ordstatPartition partitions arr[lo:hi+1] around arr[pivotIdx] and
+// returns the pivot's final position.
+func ordstatPartition(arr []float64, lo, hi, pivotIdx int) int {
+	pivot := arr[pivotIdx]
+	arr[pivotIdx], arr[hi] = arr[hi], arr[pivotIdx]
+	store := lo
+	for i := lo; i < hi; i++ {
+		if arr[i] < pivot {
+			arr[i], arr[store] = arr[store], arr[i]
+			store++
+		}
+	}
+	arr[store], arr[hi] = arr[hi], arr[store]
+	return store
+}
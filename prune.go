@@ -0,0 +1,64 @@
+// Copyright 1988 John Gilbert and Tim Peierls
+// All rights reserved.
+
+package gp
+
+// prune : Eisenstat-Liu symmetric pruning of the L columns made pivotal
+// by factoring column jcol.
+//
+// Called from the factor loop right after lucopy has finished column
+// jcol, so rperm(pivot row of jcol) = jcol and the final nonzero pattern
+// of U(:,jcol) is in place.  For every row prow found above the diagonal
+// in column jcol of U, prow is already pivotal and pcol = rperm(prow) is
+// the column it came from.  If jcol also appears in the still-active part
+// of column pcol of L, then any future DFS that walks column pcol would
+// rediscover jcol anyway, via U(prow,jcol); the entry can be moved to the
+// end of the active range and Lpend(pcol) lowered past it, so later calls
+// to ludfs stop short of it.
+//
+// Input parameters:
+//   jcol                   column just factored.
+//   lurow, lcolst, ucolst  nonzero structure of Pt(L-I+U); see lufact for
+//                          format.
+//   rperm                  the row permutation P.
+//
+// Modified parameters:
+//   Lpend                  Lpend(k) is the effective end (exclusive) of
+//                          the L part of column k for DFS purposes; see
+//                          ludfs.  Initialized by the caller to
+//                          ucolst(k+1) before column k is ever pruned.
+func prune(jcol int, lurow, lcolst, ucolst, rperm, Lpend []int) error {
+	ustart := ucolst[jcol-off]
+	uend := lcolst[jcol-off] - 1
+
+	if uend < ustart {
+		return nil
+	}
+
+	for uptr := ustart; uptr <= uend; uptr++ {
+		prow := lurow[uptr-off]
+		pcol := rperm[prow-off]
+		if pcol == 0 || pcol >= jcol {
+			continue
+		}
+
+		// Search the still-active part of column pcol of L for jcol.
+		lo := lcolst[pcol-off]
+		hi := Lpend[pcol-off] - 1
+		for lptr := lo; lptr <= hi; lptr++ {
+			if lurow[lptr-off] != jcol {
+				continue
+			}
+
+			// Swap the symmetric entry to the boundary and shrink
+			// Lpend(pcol): the rest of column pcol need not be walked
+			// again, since jcol is now reached through U(prow,jcol).
+			lurow[lptr-off] = lurow[hi-off]
+			lurow[hi-off] = jcol
+			Lpend[pcol-off] = hi
+			break
+		}
+	}
+
+	return nil
+}
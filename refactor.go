@@ -0,0 +1,181 @@
+// Copyright 1988 John Gilbert and Tim Peierls
+// All rights reserved.
+
+package gp
+
+import (
+	"fmt"
+	"math"
+)
+
+// Symbolic holds the nonzero structure and permutations produced by a
+// call to Factor, without any of the numerical values.  It can be reused
+// by Refactor or PartialRefactor for any matrix that has the same
+// sparsity pattern as the one originally passed to Factor, e.g. successive
+// Newton iterations or time steps of a transient simulation that do not
+// change the circuit/mesh topology.
+type Symbolic struct {
+	n       int
+	lurow   []int
+	lcolst  []int
+	ucolst  []int
+	rperm   []int
+	cperm   []int
+	pivot   int
+	pthresh float64
+	dthresh float64
+	nzcount int
+}
+
+// Refactor computes a new Numeric factorization of a matrix a that is
+// known to have the same nonzero pattern as the matrix originally passed
+// to Factor to produce sym.  It skips ludfs and the pivot search in
+// lucopy entirely, reusing the stored lurow, lcolst, ucolst, rperm and
+// cperm; only the numerical values are recomputed, by scattering a into
+// dense and repeating the column updates with a stripped-down lucopy
+// that pivots on the row already recorded in sym.
+//
+// Refactor is typically 3-10x faster than a full Factor call, at the
+// price of numerical safety: if a pivot that was acceptable for the
+// original matrix is zero for the new values, Refactor returns an error
+// rather than silently accepting a singular factorization.  Callers who
+// need threshold pivoting within the stored pattern, with a fallback to
+// a full Factor when a pivot fails it, should use PartialRefactor
+// instead.
+func Refactor(sym *Symbolic, a []float64, arow, acolst []int) (*Numeric, error) {
+	n := sym.n
+	lu := make([]float64, len(sym.lurow))
+	dense := make([]float64, n+1)
+	lcolst := append([]int(nil), sym.lcolst...)
+	ucolst := append([]int(nil), sym.ucolst...)
+
+	for jcol := 1; jcol <= n; jcol++ {
+		refscatter(jcol, a, arow, acolst, sym.cperm, dense)
+		refupdate(jcol, lu, sym.lurow, lcolst, ucolst, sym.rperm, dense)
+		refcopy(jcol, lu, sym.lurow, ucolst, dense)
+		if err := refscale(jcol, lu, lcolst, ucolst); err != nil {
+			return nil, fmt.Errorf("Refactor: %v", err)
+		}
+	}
+
+	return &Numeric{
+		n:      n,
+		lu:     lu,
+		lurow:  sym.lurow,
+		lcolst: lcolst,
+		ucolst: ucolst,
+		rperm:  sym.rperm,
+		cperm:  sym.cperm,
+	}, nil
+}
+
+// PartialRefactor behaves like Refactor, but re-checks the stored pivot
+// of each column against sym's threshold before dividing by it.  As soon
+// as a column's pivot fails the threshold test, PartialRefactor abandons
+// the fast path and falls back to a full Factor call on a, so the caller
+// always gets back a numerically acceptable factorization.
+func PartialRefactor(sym *Symbolic, a []float64, arow, acolst []int) (*Numeric, error) {
+	n := sym.n
+	lu := make([]float64, len(sym.lurow))
+	dense := make([]float64, n+1)
+	lcolst := append([]int(nil), sym.lcolst...)
+	ucolst := append([]int(nil), sym.ucolst...)
+
+	for jcol := 1; jcol <= n; jcol++ {
+		refscatter(jcol, a, arow, acolst, sym.cperm, dense)
+		refupdate(jcol, lu, sym.lurow, lcolst, ucolst, sym.rperm, dense)
+		refcopy(jcol, lu, sym.lurow, ucolst, dense)
+		if !refcheck(jcol, lu, lcolst, ucolst, sym.pthresh) {
+			num, _, err := Factor(a, arow, acolst, n, sym.pivot, sym.pthresh, sym.dthresh, sym.nzcount)
+			return num, err
+		}
+		if err := refscale(jcol, lu, lcolst, ucolst); err != nil {
+			return nil, fmt.Errorf("PartialRefactor: %v", err)
+		}
+	}
+
+	return &Numeric{
+		n:      n,
+		lu:     lu,
+		lurow:  sym.lurow,
+		lcolst: lcolst,
+		ucolst: ucolst,
+		rperm:  sym.rperm,
+		cperm:  sym.cperm,
+	}, nil
+}
+
+// refscatter copies column jcol of A into the dense vector, the same way
+// ludfs does, but without recording any DFS state: the fill pattern is
+// already known from sym.
+func refscatter(jcol int, a []float64, arow, acolst, cperm []int, dense []float64) {
+	nzast := acolst[cperm[jcol-off]-off]
+	nzaend := acolst[cperm[jcol-off]+1-off] - 1
+	for nzaptr := nzast; nzaptr <= nzaend; nzaptr++ {
+		krow := arow[nzaptr-off]
+		dense[krow-off] = a[nzaptr-off]
+	}
+}
+
+// refupdate applies, to the dense scatter of column jcol, the same
+// sequence of rank-1 eliminations that lucopy produced the first time
+// around, walking the above-diagonal part of column jcol of U backward
+// from the end of the reverse topological order recorded there by ludfs
+// (so each row is final before it is used) and reusing the
+// already-known pivot column of each row instead of rediscovering it.
+func refupdate(jcol int, lu []float64, lurow, lcolst, ucolst, rperm []int, dense []float64) {
+	for kptr := lcolst[jcol-off] - 1; kptr >= ucolst[jcol-off]; kptr-- {
+		krow := lurow[kptr-off]
+		pcol := rperm[krow-off]
+		ukj := dense[krow-off]
+		if ukj == 0.0 {
+			continue
+		}
+		for lptr := lcolst[pcol-off]; lptr <= ucolst[pcol+1-off]-1; lptr++ {
+			irow := lurow[lptr-off]
+			dense[irow-off] -= lu[lptr-off] * ukj
+		}
+	}
+}
+
+// refcopy moves column jcol from dense into the sparse lu array at the
+// positions recorded in sym, zeroing dense as it goes.
+func refcopy(jcol int, lu []float64, lurow, ucolst []int, dense []float64) {
+	for nzptr := ucolst[jcol-off]; nzptr <= ucolst[jcol+1-off]-1; nzptr++ {
+		irow := lurow[nzptr-off]
+		lu[nzptr-off] = dense[irow-off]
+		dense[irow-off] = 0.0
+	}
+}
+
+// refcheck reports whether the pivot stored at lcolst(jcol)-1 is still
+// acceptable under pthresh for the new numerical values, comparing it
+// against the largest magnitude remaining in the L part of the column.
+func refcheck(jcol int, lu []float64, lcolst, ucolst []int, pthresh float64) bool {
+	ujj := math.Abs(lu[lcolst[jcol-off]-1-off])
+	if ujj == 0.0 {
+		return false
+	}
+
+	maxpiv := 0.0
+	for nzptr := lcolst[jcol-off]; nzptr <= ucolst[jcol+1-off]-1; nzptr++ {
+		if utemp := math.Abs(lu[nzptr-off]); utemp > maxpiv {
+			maxpiv = utemp
+		}
+	}
+
+	return ujj >= pthresh*maxpiv
+}
+
+// refscale divides column jcol of L by its stored pivot U(jcol,jcol).
+func refscale(jcol int, lu []float64, lcolst, ucolst []int) error {
+	ujjptr := lcolst[jcol-off] - 1
+	ujj := lu[ujjptr-off]
+	if ujj == 0.0 {
+		return fmt.Errorf("numerically zero diagonal element at column %v", jcol)
+	}
+	for nzptr := lcolst[jcol-off]; nzptr <= ucolst[jcol+1-off]-1; nzptr++ {
+		lu[nzptr-off] /= ujj
+	}
+	return nil
+}
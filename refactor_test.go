@@ -0,0 +1,75 @@
+// Copyright 1988 John Gilbert and Tim Peierls
+// All rights reserved.
+
+package gp
+
+import (
+	"math"
+	"testing"
+)
+
+// TestRefactorSolve drives Factor once to obtain a Symbolic for
+//
+//	A = [3  5]
+//	    [6 17]
+//
+// and then calls Refactor on that same matrix's values, checking that the
+// resulting Numeric solves Ax = b the same way the one Factor itself
+// produced does. This exercises refscatter, refupdate, refcopy and
+// refscale together, not just that Refactor returns without error.
+func TestRefactorSolve(t *testing.T) {
+	a := []float64{0, 3, 6, 5, 17}
+	arow := []int{0, 1, 2, 1, 2}
+	acolst := []int{0, 1, 3, 5}
+
+	_, sym, err := Factor(a, arow, acolst, 2, 0, 0.0, 0.0, 0)
+	if err != nil {
+		t.Fatalf("Factor: %v", err)
+	}
+
+	num, err := Refactor(sym, a, arow, acolst)
+	if err != nil {
+		t.Fatalf("Refactor: %v", err)
+	}
+
+	x, err := num.Solve([]float64{1, 0})
+	if err != nil {
+		t.Fatalf("Solve: %v", err)
+	}
+	wantX := []float64{17.0 / 21.0, -2.0 / 7.0}
+	for i := range wantX {
+		if math.Abs(x[i]-wantX[i]) > 1e-9 {
+			t.Errorf("Solve: x[%v] = %v, want %v", i, x[i], wantX[i])
+		}
+	}
+}
+
+// TestPartialRefactorSolve checks the fast path of PartialRefactor (the
+// stored pivots all still pass threshold, so no fallback to Factor is
+// needed) against the same fixture as TestRefactorSolve.
+func TestPartialRefactorSolve(t *testing.T) {
+	a := []float64{0, 3, 6, 5, 17}
+	arow := []int{0, 1, 2, 1, 2}
+	acolst := []int{0, 1, 3, 5}
+
+	_, sym, err := Factor(a, arow, acolst, 2, 0, 0.0, 0.0, 0)
+	if err != nil {
+		t.Fatalf("Factor: %v", err)
+	}
+
+	num, err := PartialRefactor(sym, a, arow, acolst)
+	if err != nil {
+		t.Fatalf("PartialRefactor: %v", err)
+	}
+
+	x, err := num.Solve([]float64{1, 0})
+	if err != nil {
+		t.Fatalf("Solve: %v", err)
+	}
+	wantX := []float64{17.0 / 21.0, -2.0 / 7.0}
+	for i := range wantX {
+		if math.Abs(x[i]-wantX[i]) > 1e-9 {
+			t.Errorf("Solve: x[%v] = %v, want %v", i, x[i], wantX[i])
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 1988 John Gilbert and Tim Peierls
+// All rights reserved.
+
+package gp
+
+import "fmt"
+
+// Solve solves Ax = b, given the factorization PAQ = LU stored in n
+// (rperm = P, cperm = Q, L unit lower triangular with its diagonal not
+// stored, U upper triangular with its diagonal the stored pivots).  The
+// system is solved in three steps: permute b by P, forward-solve the
+// unit lower triangular system Lw = Pb, back-solve the upper triangular
+// system Uy = w, and permute y by Q to get x.
+//
+// b and the returned vector are ordinary 0-based Go slices of length
+// n.n; the 1-based off-indexed convention used internally, and by
+// rperm/cperm, is only converted to and from at this boundary.
+func (n *Numeric) Solve(b []float64) ([]float64, error) {
+	if len(b) != n.n {
+		return nil, fmt.Errorf("Solve: b has length %v, want %v", len(b), n.n)
+	}
+
+	w := make([]float64, n.n+1)
+	for r := 1; r <= n.n; r++ {
+		w[n.rperm[r-off]-off] = b[r-1]
+	}
+
+	// Forward-solve Lw = Pb: L is unit lower triangular, so w(jcol) is
+	// already final as soon as it is reached; push its contribution
+	// into the rows below it, which have not been finalized yet.  lurow
+	// numbers rows according to A, so each is mapped through rperm to
+	// its row of PA.
+	for jcol := 1; jcol <= n.n; jcol++ {
+		wj := w[jcol-off]
+		for nzptr := n.lcolst[jcol-off]; nzptr <= n.ucolst[jcol+1-off]-1; nzptr++ {
+			irow := n.lurow[nzptr-off]
+			w[n.rperm[irow-off]-off] -= n.lu[nzptr-off] * wj
+		}
+	}
+
+	// Back-solve Uy = w: divide by the stored pivot, then push the
+	// result into the rows above, which have not been finalized yet.
+	y := w
+	for jcol := n.n; jcol >= 1; jcol-- {
+		ujj := n.lu[n.lcolst[jcol-off]-1-off]
+		y[jcol-off] /= ujj
+		for nzptr := n.ucolst[jcol-off]; nzptr <= n.lcolst[jcol-off]-2; nzptr++ {
+			irow := n.lurow[nzptr-off]
+			y[n.rperm[irow-off]-off] -= n.lu[nzptr-off] * y[jcol-off]
+		}
+	}
+
+	x := make([]float64, n.n)
+	for jcol := 1; jcol <= n.n; jcol++ {
+		x[n.cperm[jcol-off]-1] = y[jcol-off]
+	}
+
+	return x, nil
+}
+
+// SolveTranspose solves A^T y = b, given the factorization PAQ = LU
+// stored in n (rperm = P, cperm = Q, L unit lower triangular with its
+// diagonal not stored, U upper triangular with its diagonal the stored
+// pivots).  Since A^T = Q U^T L^T P, the system is solved in three
+// steps: permute b by Q^-1, forward-solve the lower triangular system
+// U^T w = Q^-1 b, back-solve the upper triangular system L^T z = w, and
+// permute z by P^-1 to get y.
+//
+// b and the returned vector are ordinary 0-based Go slices of length
+// n.n; the 1-based off-indexed convention used internally, and by
+// rperm/cperm, is only converted to and from at this boundary.
+//
+// SolveTranspose is the transpose counterpart of Solve, and is needed by
+// CondEst's Hager iteration; it is also useful on its own whenever a
+// transposed solve is wanted without factoring A^T separately.
+func (n *Numeric) SolveTranspose(b []float64) ([]float64, error) {
+	if len(b) != n.n {
+		return nil, fmt.Errorf("SolveTranspose: b has length %v, want %v", len(b), n.n)
+	}
+
+	w := make([]float64, n.n+1)
+	for c := 1; c <= n.n; c++ {
+		w[c-off] = b[n.cperm[c-off]-1]
+	}
+
+	// Forward-solve U^T w = Q^-1 b: column jcol of U becomes row jcol of
+	// U^T, so before w(jcol) can be finished, every earlier row's
+	// contribution through that row of U^T (i.e. column jcol's own U
+	// part) must first be gathered into it; only then does dividing by
+	// ujj finish w(jcol).  As in Solve, lurow rows are mapped through
+	// rperm to their rows of PA.
+	for jcol := 1; jcol <= n.n; jcol++ {
+		for nzptr := n.ucolst[jcol-off]; nzptr <= n.lcolst[jcol-off]-2; nzptr++ {
+			irow := n.lurow[nzptr-off]
+			w[jcol-off] -= n.lu[nzptr-off] * w[n.rperm[irow-off]-off]
+		}
+		ujj := n.lu[n.lcolst[jcol-off]-1-off]
+		w[jcol-off] /= ujj
+	}
+
+	// Back-solve L^T z = w: column jcol of L (unit diagonal, not stored)
+	// becomes row jcol of L^T, so process columns in reverse.
+	z := append([]float64(nil), w...)
+	for jcol := n.n; jcol >= 1; jcol-- {
+		for nzptr := n.lcolst[jcol-off]; nzptr <= n.ucolst[jcol+1-off]-1; nzptr++ {
+			irow := n.lurow[nzptr-off]
+			z[jcol-off] -= n.lu[nzptr-off] * z[n.rperm[irow-off]-off]
+		}
+	}
+
+	y := make([]float64, n.n)
+	for r := 1; r <= n.n; r++ {
+		y[r-1] = z[n.rperm[r-off]-off]
+	}
+
+	return y, nil
+}
@@ -0,0 +1,66 @@
+// Copyright 1988 John Gilbert and Tim Peierls
+// All rights reserved.
+
+package gp
+
+import (
+	"math"
+	"testing"
+)
+
+// fixture2x2 returns the Numeric for A = LU with
+//
+//	L = [1 0]   U = [3 5]
+//	    [2 1]       [0 7]
+//
+// under the identity row and column permutation, i.e. A = [[3,5],[6,17]].
+func fixture2x2() *Numeric {
+	return &Numeric{
+		n:      2,
+		lu:     []float64{0, 3, 2, 5, 7},
+		lurow:  []int{0, 1, 2, 1, 2},
+		lcolst: []int{0, 2, 5},
+		ucolst: []int{0, 1, 3, 5},
+		rperm:  []int{0, 1, 2},
+		cperm:  []int{0, 1, 2},
+	}
+}
+
+func TestSolve2x2(t *testing.T) {
+	n := fixture2x2()
+
+	x, err := n.Solve([]float64{1, 0})
+	if err != nil {
+		t.Fatalf("Solve: %v", err)
+	}
+
+	want := []float64{17.0 / 21.0, -2.0 / 7.0}
+	for i := range want {
+		if math.Abs(x[i]-want[i]) > 1e-9 {
+			t.Errorf("Solve: x[%v] = %v, want %v", i, x[i], want[i])
+		}
+	}
+}
+
+// TestSolveTranspose2x2 checks SolveTranspose against a known small
+// factorization: A = LU with L = [[1,0],[2,1]], U = [[3,5],[0,7]], under
+// the identity permutation, so A^T = [[3,6],[5,17]].  Solving A^T y = b
+// for b = [1,0] by hand gives y = [17/21, -5/21], i.e. roughly
+// [0.810, -0.238] -- a prior version of the forward-solve loop divided
+// w(jcol) by U(jcol,jcol) before gathering column jcol's own U-part
+// contributions into it, and returned [1/3, 0] instead.
+func TestSolveTranspose2x2(t *testing.T) {
+	n := fixture2x2()
+
+	y, err := n.SolveTranspose([]float64{1, 0})
+	if err != nil {
+		t.Fatalf("SolveTranspose: %v", err)
+	}
+
+	want := []float64{17.0 / 21.0, -5.0 / 21.0}
+	for i := range want {
+		if math.Abs(y[i]-want[i]) > 1e-9 {
+			t.Errorf("SolveTranspose: y[%v] = %v, want %v", i, y[i], want[i])
+		}
+	}
+}
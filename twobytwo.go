@@ -0,0 +1,149 @@
+// Copyright 1988 John Gilbert and Tim Peierls
+// All rights reserved.
+
+package gp
+
+import "math"
+
+// TwoByTwo implements the UMFPACK "2-by-2" strategy: a preprocessing
+// pass, run before the main column ordering and before lucopy, that
+// permutes rows of a to move numerically strong entries onto the
+// diagonal.  It targets matrices whose natural diagonal is zero or tiny
+// (KKT systems, saddle-point problems, index-1 DAEs), where lucopy would
+// otherwise have no usable pivot to start from.
+//
+// For each column j, an off-diagonal entry (i,j) is marked "strong" if
+// |a(i,j)| >= tol*max_k|a(k,j)|.  TwoByTwo builds the bipartite graph of
+// strong entries and finds a maximum matching of columns to rows by
+// augmenting paths; for every column j whose own diagonal a(j,j) is weak
+// or structurally absent, and that the matching pairs with a row i != j,
+// row i is moved to position j of the returned row permutation.  Every
+// other row keeps its own position if that is still free; the rows left
+// over fill the remaining positions in increasing order, so two columns
+// matched to each other's rows give a swap of those rows.
+//
+// TwoByTwo returns the row permutation rperm, the identity column
+// permutation cperm (columns are not reordered by this pass), and the
+// number of columns the matching could not pair with a strong row, so
+// that callers can decide whether to accept the reordering.
+func TwoByTwo(a *CSC, tol float64) (rperm, cperm []int, nunmatched int) {
+	n := a.N
+	cperm = make([]int, n+1)
+	for i := 1; i <= n; i++ {
+		cperm[i] = i
+	}
+
+	strong := strongRows(a, tol)
+
+	rowmatch := make([]int, n+1)
+	colmatch := make([]int, n+1)
+	for j := 1; j <= n; j++ {
+		visited := make([]bool, n+1)
+		augment(j, strong, rowmatch, colmatch, visited)
+	}
+
+	// Build rperm directly from the matching rather than swapping an
+	// identity array pairwise per-column: a column swapped into place
+	// while processing column j must stay there when column i, its swap
+	// partner, is processed in turn, so the permutation has to be
+	// assembled from colmatch as a whole, not folded in one column at a
+	// time.
+	rperm = make([]int, n+1)
+	targetTaken := make([]bool, n+1)
+	sourceUsed := make([]bool, n+1)
+	for j := 1; j <= n; j++ {
+		i := colmatch[j]
+		if i == 0 {
+			nunmatched++
+			continue
+		}
+		if i != j && weakDiagonal(a, j, tol) {
+			rperm[i] = j
+			sourceUsed[i] = true
+			targetTaken[j] = true
+		}
+	}
+
+	var leftoverRows, leftoverCols []int
+	for r := 1; r <= n; r++ {
+		if sourceUsed[r] {
+			continue
+		}
+		if targetTaken[r] {
+			leftoverRows = append(leftoverRows, r)
+			continue
+		}
+		rperm[r] = r
+		targetTaken[r] = true
+	}
+	for j := 1; j <= n; j++ {
+		if !targetTaken[j] {
+			leftoverCols = append(leftoverCols, j)
+		}
+	}
+	for k, r := range leftoverRows {
+		rperm[r] = leftoverCols[k]
+	}
+
+	return rperm, cperm, nunmatched
+}
+
+// strongRows returns, for each column j, the rows of a "strong" entries
+// in that column: |a(i,j)| >= tol*max_k|a(k,j)|.
+func strongRows(a *CSC, tol float64) [][]int {
+	n := a.N
+	strong := make([][]int, n+1)
+	for j := 1; j <= n; j++ {
+		thresh := tol * colMax(a, j)
+		for nzptr := a.Acolst[j-off]; nzptr <= a.Acolst[j+1-off]-1; nzptr++ {
+			i := a.Arow[nzptr-off]
+			if math.Abs(a.A[nzptr-off]) >= thresh {
+				strong[j] = append(strong[j], i)
+			}
+		}
+	}
+	return strong
+}
+
+// colMax returns the largest magnitude entry in column j of a.
+func colMax(a *CSC, j int) float64 {
+	maxv := 0.0
+	for nzptr := a.Acolst[j-off]; nzptr <= a.Acolst[j+1-off]-1; nzptr++ {
+		if v := math.Abs(a.A[nzptr-off]); v > maxv {
+			maxv = v
+		}
+	}
+	return maxv
+}
+
+// weakDiagonal reports whether column j's own diagonal entry a(j,j) is
+// zero, structurally absent, or small relative to the rest of the
+// column.
+func weakDiagonal(a *CSC, j int, tol float64) bool {
+	diag := 0.0
+	for nzptr := a.Acolst[j-off]; nzptr <= a.Acolst[j+1-off]-1; nzptr++ {
+		if a.Arow[nzptr-off] == j {
+			diag = math.Abs(a.A[nzptr-off])
+			break
+		}
+	}
+	return diag < tol*colMax(a, j)
+}
+
+// augment looks for an augmenting path out of column col in the
+// bipartite graph of strong entries, extending the matching rowmatch/
+// colmatch in place if one is found.
+func augment(col int, strong [][]int, rowmatch, colmatch []int, visited []bool) bool {
+	for _, row := range strong[col] {
+		if visited[row] {
+			continue
+		}
+		visited[row] = true
+		if rowmatch[row] == 0 || augment(rowmatch[row], strong, rowmatch, colmatch, visited) {
+			rowmatch[row] = col
+			colmatch[col] = row
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+// Copyright 1988 John Gilbert and Tim Peierls
+// All rights reserved.
+
+package gp
+
+import "testing"
+
+// TestTwoByTwoMutualMatch covers the canonical case this pass targets: a
+// column 1 with a strong diagonal that stays in place, and columns 2
+// and 3 with no diagonal entry, whose off-diagonal
+// strong entries mutually match each other's row rather than their own
+// (column 2's only strong entry is in row 3, column 3's only strong
+// entry is in row 2). A prior version built rperm by swapping an
+// identity array in place, column by column, which for this exact
+// shape applies the 2<->3 swap once for each column and cancels back
+// to the identity permutation.
+func TestTwoByTwoMutualMatch(t *testing.T) {
+	// Column 1: a strong diagonal at row 1, needing no swap.
+	// Column 2: structurally no diagonal; its only (strong) entry is in
+	// row 3.
+	// Column 3: structurally no diagonal; its only (strong) entry is in
+	// row 2.
+	a := &CSC{
+		N:      3,
+		A:      []float64{0, 5.0, 5.0, 5.0},
+		Arow:   []int{0, 1, 3, 2},
+		Acolst: []int{0, 1, 2, 3, 4},
+	}
+
+	rperm, cperm, nunmatched := TwoByTwo(a, 0.1)
+
+	if nunmatched != 0 {
+		t.Fatalf("nunmatched = %v, want 0 (every column matched)", nunmatched)
+	}
+
+	want := []int{0, 1, 3, 2}
+	for i := 1; i <= 3; i++ {
+		if rperm[i] != want[i] {
+			t.Errorf("rperm[%v] = %v, want %v (rperm = %v)", i, rperm[i], want[i], rperm)
+		}
+	}
+
+	for i := 1; i <= 3; i++ {
+		if cperm[i] != i {
+			t.Errorf("cperm[%v] = %v, want identity", i, cperm[i])
+		}
+	}
+}